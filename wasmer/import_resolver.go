@@ -0,0 +1,126 @@
+package wasmer
+
+// ImportResolver is an experimental alternative to pre-building an
+// `Imports` map: instead of registering every import a module could
+// possibly need, the runtime calls the resolver the first time each
+// import declared by the module is looked up, and caches whatever is
+// returned for the lifetime of the instance. This lets an embedder
+// share one heavy import registry (for example, a set of blockchain
+// host functions) across many instances without copying it into an
+// `Imports` value per module.
+//
+// `ok` must be `false` when the resolver has nothing for the given
+// `namespace`/`name` pair; the instantiation then fails the same way
+// it would have if the import were simply missing from an `Imports`
+// map.
+type ImportResolver func(namespace string, name string) (*ImportedFunction, bool)
+
+// NewInstanceWithResolver constructs a new `Instance`, resolving each
+// import declared by the module lazily through `resolver` instead of
+// requiring a pre-built `Imports` map.
+//
+// The module compiled to resolve the imports is only needed for that
+// one instantiation and is never exposed to the caller, so it is
+// closed before returning; keeping it open for every call would leak a
+// `cWasmerModuleT` per instantiation.
+func NewInstanceWithResolver(bytes []byte, resolver ImportResolver) (Instance, error) {
+	module, err := CompileModule(bytes)
+	if err != nil {
+		return Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}, err
+	}
+	defer module.Close()
+
+	imports, err := resolveModuleImports(module, resolver)
+	if err != nil {
+		return Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}, err
+	}
+
+	instance, err := module.InstantiateWithImports(imports)
+	instance.module = nil
+
+	return instance, err
+}
+
+// NewMeteredInstanceWithResolver is the gas-metered counterpart of
+// `NewInstanceWithResolver`. As with `NewInstanceWithResolver`, the
+// module compiled to resolve the imports is closed before returning.
+func NewMeteredInstanceWithResolver(
+	bytes []byte,
+	resolver ImportResolver,
+	gasLimit uint64,
+	opcode_costs *[OPCODE_COUNT]uint32,
+) (Instance, error) {
+	module, err := CompileModule(bytes)
+	if err != nil {
+		return Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}, err
+	}
+	defer module.Close()
+
+	imports, err := resolveModuleImports(module, resolver)
+	if err != nil {
+		return Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}, err
+	}
+
+	importObject, err := NewImportObject(imports)
+	if err != nil {
+		return Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}, err
+	}
+	defer importObject.Close()
+
+	instance, err := module.InstantiateMeteredWithImportObject(&importObject, gasLimit, opcode_costs)
+	instance.module = nil
+
+	return instance, err
+}
+
+// resolveModuleImports walks the imports declared by `module` and asks
+// `resolver` for each of them, building an `Imports` value that caches
+// exactly the functions the module actually needs. The resulting
+// `Imports` is owned by the instance it gets attached to, so the
+// resolved functions are freed the same way eagerly-registered ones
+// are: by `Instance.Close`.
+//
+// A resolver is expected to hand back the same `*ImportedFunction` for
+// a given `namespace`/`name` pair across many calls (that sharing is
+// the whole point of `ImportResolver`), so the value it returns must be
+// treated as read-only and never stored as-is: `generateWasmerImports`
+// overwrites `importedFunctionPointer` on every instantiation, and
+// `Instance.Close` frees it, so two instances resolving the same import
+// concurrently would race on that field and then double-free the same
+// cgo pointer. Each resolved entry is therefore copied before it is
+// adjusted and stored.
+func resolveModuleImports(module *Module, resolver ImportResolver) (*Imports, error) {
+	declaredImports := cWasmerModuleImports(module.module)
+	imports := NewImports()
+
+	for _, declaredImport := range declaredImports {
+		resolved, ok := resolver(declaredImport.namespace, declaredImport.name)
+		if !ok {
+			// Left out of `imports`, so the instantiation reports it
+			// as a missing import.
+			continue
+		}
+
+		importedFunction := *resolved
+
+		// `generateWasmerImports` binds the C import using
+		// `importedFunction.namespace`, not the map key it is stored
+		// under; trust the module's declared namespace over whatever
+		// the resolver happened to set.
+		importedFunction.namespace = declaredImport.namespace
+
+		// This instance gets its own cgo import pointer from
+		// `generateWasmerImports`; it must not start out aliasing
+		// whatever pointer a previous instantiation of the shared
+		// `resolved` value left behind.
+		importedFunction.importedFunctionPointer = nil
+
+		if imports.imports[declaredImport.namespace] == nil {
+			imports.imports[declaredImport.namespace] = make(map[string]*ImportedFunction)
+		}
+
+		imports.imports[declaredImport.namespace][declaredImport.name] = &importedFunction
+	}
+
+	return imports, nil
+}
@@ -57,6 +57,13 @@ type Instance struct {
 	// constructor to set it.
 	imports *Imports
 
+	// The `Module` this instance was created from, if any. It is set
+	// when the instance comes from `Module.InstantiateWithImports` or
+	// `Module.InstantiateMeteredWithImportObject`, and is never closed
+	// by `Instance.Close`: the module is owned by whoever compiled it
+	// and may be instantiated again.
+	module *Module
+
 	// All functions exported by the WebAssembly instance, indexed
 	// by their name as a string. An exported function is a
 	// regular variadic Go closure. Arguments are untyped. Since
@@ -72,6 +79,13 @@ type Instance struct {
 	// standard Go type.
 	Exports map[string]func(...interface{}) (Value, error)
 
+	// Every function exported by the WebAssembly instance, indexed by
+	// name, returning the full `Values` slice produced by the call
+	// instead of squashing it into a single `Value`. Use this for
+	// exported functions declared with more than one result (the wasm
+	// multi-value proposal); `Exports` only supports arity 0 and 1.
+	ExportsMulti map[string]func(...interface{}) (Values, error)
+
 	// The exported memory of a WebAssembly instance.
 	Memory *Memory
 }
@@ -110,6 +124,22 @@ func NewImportObject(imports *Imports) (ImportObject, error) {
 	return importObject, nil
 }
 
+// Close closes/frees an `ImportObject`'s underlying `cWasmerImportObjectT`.
+// It does not touch the `Imports` it was built from: that value is
+// still owned by whoever constructed it and is freed separately, by
+// `Instance.Close` or `Imports.Close`.
+//
+// Most callers don't need this: an `ImportObject` meant to be reused
+// across many instances (such as `InstancePool`'s) should stay open for
+// as long as those instances do, and its creator is responsible for
+// closing it once they're all done. Call `Close` when an `ImportObject`
+// was only ever needed for a single instantiation.
+func (importObject *ImportObject) Close() {
+	if importObject.c_import_object != nil {
+		cWasmerImportObjectDestroy(importObject.c_import_object)
+	}
+}
+
 // NewInstance constructs a new `Instance` with no imported functions.
 func NewInstance(bytes []byte) (Instance, error) {
 	return NewInstanceWithImports(bytes, NewImports())
@@ -185,6 +215,18 @@ func NewMeteredInstanceWithImports(
 	return instance, err
 }
 
+// NewMeteredInstanceWithImportsAndCosts is `NewMeteredInstanceWithImports`,
+// but accepting a named `OpcodeCosts` table instead of a bare
+// `*[OPCODE_COUNT]uint32`.
+func NewMeteredInstanceWithImportsAndCosts(
+	bytes []byte,
+	imports *Imports,
+	gasLimit uint64,
+	opcodeCosts *OpcodeCosts,
+) (Instance, error) {
+	return NewMeteredInstanceWithImports(bytes, imports, gasLimit, opcodeCosts.ToArray())
+}
+
 func NewMeteredInstanceWithImportObject(
 	bytes []byte,
 	importObject *ImportObject,
@@ -220,6 +262,18 @@ func NewMeteredInstanceWithImportObject(
 	return instance, err
 }
 
+// NewMeteredInstanceWithImportObjectAndCosts is
+// `NewMeteredInstanceWithImportObject`, but accepting a named
+// `OpcodeCosts` table instead of a bare `*[OPCODE_COUNT]uint32`.
+func NewMeteredInstanceWithImportObjectAndCosts(
+	bytes []byte,
+	importObject *ImportObject,
+	gasLimit uint64,
+	opcodeCosts *OpcodeCosts,
+) (Instance, error) {
+	return NewMeteredInstanceWithImportObject(bytes, importObject, gasLimit, opcodeCosts.ToArray())
+}
+
 func newInstanceWithImports(
 	c_instance *cWasmerInstanceT,
 	imports *Imports,
@@ -233,7 +287,7 @@ func newInstanceWithImports(
 	cWasmerInstanceExports(c_instance, &wasmExports)
 	defer cWasmerExportsDestroy(wasmExports)
 
-	exports, err := retrieveExportedFunctions(c_instance, wasmExports)
+	exports, exportsMulti, err := retrieveExportedFunctions(c_instance, wasmExports)
 	if err != nil {
 		return emptyInstance, err
 	}
@@ -244,10 +298,10 @@ func newInstanceWithImports(
 	}
 
 	if hasMemory == false {
-		return Instance{instance: c_instance, imports: imports, Exports: exports, Memory: nil}, nil
+		return Instance{instance: c_instance, imports: imports, Exports: exports, ExportsMulti: exportsMulti, Memory: nil}, nil
 	}
 
-	return Instance{instance: c_instance, imports: imports, Exports: exports, Memory: &memory}, nil
+	return Instance{instance: c_instance, imports: imports, Exports: exports, ExportsMulti: exportsMulti, Memory: &memory}, nil
 }
 
 // HasMemory checks whether the instance has at least one exported memory.
@@ -289,3 +343,12 @@ func (instance *Instance) GetPointsUsed() uint64 {
 func (instance *Instance) SetPointsUsed(points uint64) {
 	cWasmerInstanceSetPointsUsed(instance.instance, points)
 }
+
+// SetGasLimitAndCosts resets the gas limit and opcode cost table of an
+// already-instantiated metered instance, without paying the cost of a
+// fresh `cWasmerInstantiate` call. It exists primarily for
+// `InstancePool`, which reuses instances across acquisitions.
+func (instance *Instance) SetGasLimitAndCosts(gasLimit uint64, opcodeCosts *[OPCODE_COUNT]uint32) {
+	cWasmerInstanceSetGasLimit(instance.instance, gasLimit)
+	cWasmerInstanceSetOpcodeCosts(instance.instance, opcodeCosts)
+}
@@ -79,8 +79,9 @@ func retrieveExportedMemory(wasmExports *cWasmerExportsT) (Memory, bool, error)
 	return memory, hasMemory, nil
 }
 
-func retrieveExportedFunctions(c_instance *cWasmerInstanceT, wasmExports *cWasmerExportsT) (map[string]func(...interface{}) (Value, error), error) {
+func retrieveExportedFunctions(c_instance *cWasmerInstanceT, wasmExports *cWasmerExportsT) (map[string]func(...interface{}) (Value, error), map[string]func(...interface{}) (Values, error), error) {
 	var exports = make(map[string]func(...interface{}) (Value, error))
+	var exportsMulti = make(map[string]func(...interface{}) (Values, error))
 
 	var numberOfExports = int(cWasmerExportsLen(wasmExports))
 
@@ -96,57 +97,14 @@ func retrieveExportedFunctions(c_instance *cWasmerInstanceT, wasmExports *cWasme
 		var wasmFunction = cWasmerExportToFunc(wasmExport)
 		var exportedFunctionName = cGoStringN((*cChar)(unsafe.Pointer(wasmExportName.bytes)), (cInt)(wasmExportName.bytes_len))
 
-		wrappedWasmFunction, err := createExportedFunctionWrapper(c_instance, wasmFunction, exportedFunctionName)
+		wrappedWasmFunctionMulti, err := createExportedFunctionMultiWrapper(c_instance, wasmFunction, exportedFunctionName)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		exports[exportedFunctionName] = wrappedWasmFunction
+		exportsMulti[exportedFunctionName] = wrappedWasmFunctionMulti
+		exports[exportedFunctionName] = singleValueWrapper(wrappedWasmFunctionMulti, exportedFunctionName)
 	}
 
-	return exports, nil
-}
-
-func createExportedFunctionWrapper(
-	c_instance *cWasmerInstanceT,
-	wasmFunction *cWasmerExportFuncT,
-	exportedFunctionName string,
-) (func(...interface{}) (Value, error), error) {
-	wasmFunctionInputSignatures, wasmFunctionInputsArity, err := getExportedFunctionSignature(wasmFunction, exportedFunctionName)
-	if err != nil {
-		return nil, err
-	}
-
-	wasmFunctionOutputsArity, err := getExportedFunctionOutputArity(wasmFunction, exportedFunctionName)
-	if err != nil {
-		return nil, err
-	}
-
-	wrapper := func(arguments ...interface{}) (Value, error) {
-		err := validateGivenArguments(exportedFunctionName, arguments, wasmFunctionInputsArity)
-		if err != nil {
-			return void(), err
-		}
-
-		wasmInputs, err := createWasmInputsFromArguments(arguments, wasmFunctionInputsArity, wasmFunctionInputSignatures, exportedFunctionName)
-		if err != nil {
-			return void(), err
-		}
-
-		wasmOutputs, callResult := callWasmFunction(
-			c_instance,
-			exportedFunctionName,
-			wasmFunctionInputsArity,
-			wasmFunctionOutputsArity,
-			wasmInputs,
-		)
-
-		if callResult != cWasmerOk {
-			return void(), NewExportedFunctionError(exportedFunctionName, "Failed to call the `%s` exported function.")
-		}
-
-		value, err := convertWasmOutputToValue(wasmFunctionOutputsArity, wasmOutputs, exportedFunctionName)
-		return value, err
-	}
-	return wrapper, nil
+	return exports, exportsMulti, nil
 }
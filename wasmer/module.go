@@ -0,0 +1,198 @@
+package wasmer
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Module represents a compiled WebAssembly module. Unlike `Instance`,
+// which is created directly from wasm bytes and pays the compilation
+// cost every time, a `Module` is compiled once and can be instantiated
+// any number of times via `InstantiateWithImports`. This matters for
+// workloads — such as a blockchain executing the same contract many
+// times — where the same bytes would otherwise be recompiled on every
+// call.
+type Module struct {
+	// The underlying WebAssembly module.
+	module *cWasmerModuleT
+}
+
+// CompileModule compiles the given WebAssembly bytes into a `Module`
+// without instantiating it.
+func CompileModule(bytes []byte) (*Module, error) {
+	var c_module *cWasmerModuleT
+
+	var compileResult = cWasmerCompile(
+		&c_module,
+		(*cUchar)(unsafe.Pointer(&bytes[0])),
+		cUint(len(bytes)),
+	)
+
+	if compileResult != cWasmerOk {
+		var lastError, err = GetLastError()
+		var errorMessage = "Failed to compile the module:\n    %s"
+
+		if err != nil {
+			errorMessage = fmt.Sprintf(errorMessage, "(unknown details)")
+		} else {
+			errorMessage = fmt.Sprintf(errorMessage, lastError)
+		}
+
+		return nil, NewInstanceError(errorMessage)
+	}
+
+	return &Module{module: c_module}, nil
+}
+
+// Serialize serializes the module into a byte slice that can be
+// persisted to disk and later restored with `DeserializeModule`,
+// skipping compilation entirely on the next load (a "headless" engine
+// that only ever instantiates, never compiles).
+func (module *Module) Serialize() ([]byte, error) {
+	var c_serialized_module *cWasmerSerializedModuleT
+
+	var serializeResult = cWasmerModuleSerialize(&c_serialized_module, module.module)
+
+	if serializeResult != cWasmerOk {
+		var lastError, err = GetLastError()
+		var errorMessage = "Failed to serialize the module:\n    %s"
+
+		if err != nil {
+			errorMessage = fmt.Sprintf(errorMessage, "(unknown details)")
+		} else {
+			errorMessage = fmt.Sprintf(errorMessage, lastError)
+		}
+
+		return nil, NewInstanceError(errorMessage)
+	}
+	defer cWasmerSerializedModuleDestroy(c_serialized_module)
+
+	var byteArray = cWasmerSerializedModuleBytes(c_serialized_module)
+	var bytes = cGoBytes((*cChar)(unsafe.Pointer(byteArray.bytes)), (cInt)(byteArray.bytes_len))
+
+	return bytes, nil
+}
+
+// DeserializeModule restores a `Module` previously produced by
+// `Serialize`. The bytes must have been produced by a compatible
+// wasmer build; passing arbitrary wasm bytes here is an error, use
+// `CompileModule` for that instead.
+func DeserializeModule(bytes []byte) (*Module, error) {
+	var c_module *cWasmerModuleT
+
+	var deserializeResult = cWasmerModuleDeserialize(
+		&c_module,
+		(*cUchar)(unsafe.Pointer(&bytes[0])),
+		cUint(len(bytes)),
+	)
+
+	if deserializeResult != cWasmerOk {
+		var lastError, err = GetLastError()
+		var errorMessage = "Failed to deserialize the module:\n    %s"
+
+		if err != nil {
+			errorMessage = fmt.Sprintf(errorMessage, "(unknown details)")
+		} else {
+			errorMessage = fmt.Sprintf(errorMessage, lastError)
+		}
+
+		return nil, NewInstanceError(errorMessage)
+	}
+
+	return &Module{module: c_module}, nil
+}
+
+// InstantiateWithImports instantiates the module with the given
+// imported functions. The module can be instantiated any number of
+// times; each call produces an independent `Instance` that shares the
+// module's compiled code.
+func (module *Module) InstantiateWithImports(imports *Imports) (Instance, error) {
+	wasmImportsCPointer, numberOfImports := generateWasmerImports(imports)
+
+	var c_instance *cWasmerInstanceT
+
+	var instantiateResult = cWasmerModuleInstantiate(
+		module.module,
+		&c_instance,
+		wasmImportsCPointer,
+		cInt(numberOfImports),
+	)
+
+	if instantiateResult != cWasmerOk {
+		var lastError, err = GetLastError()
+		var errorMessage = "Failed to instantiate the module:\n    %s"
+
+		if err != nil {
+			errorMessage = fmt.Sprintf(errorMessage, "(unknown details)")
+		} else {
+			errorMessage = fmt.Sprintf(errorMessage, lastError)
+		}
+
+		var emptyInstance = Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}
+		return emptyInstance, NewInstanceError(errorMessage)
+	}
+
+	instance, err := newInstanceWithImports(c_instance, imports)
+	instance.module = module
+
+	return instance, err
+}
+
+// InstantiateMeteredWithImportObject instantiates the module with a
+// pre-built `ImportObject` and gas metering enabled, following the
+// same semantics as `NewMeteredInstanceWithImportObject`.
+func (module *Module) InstantiateMeteredWithImportObject(
+	importObject *ImportObject,
+	gasLimit uint64,
+	opcode_costs *[OPCODE_COUNT]uint32,
+) (Instance, error) {
+	var c_instance *cWasmerInstanceT
+
+	var instantiateResult = cWasmerModuleInstantiateWithMeteringAndImportObject(
+		module.module,
+		&c_instance,
+		importObject.c_import_object,
+		gasLimit,
+		opcode_costs,
+	)
+
+	if instantiateResult != cWasmerOk {
+		var lastError, err = GetLastError()
+		var errorMessage = "Failed to instantiate the module:\n    %s"
+
+		if err != nil {
+			errorMessage = fmt.Sprintf(errorMessage, "(unknown details)")
+		} else {
+			errorMessage = fmt.Sprintf(errorMessage, lastError)
+		}
+
+		var emptyInstance = Instance{instance: nil, imports: nil, Exports: nil, Memory: nil}
+		return emptyInstance, NewInstanceError(errorMessage)
+	}
+
+	instance, err := newInstanceWithImports(c_instance, importObject.imports)
+	instance.module = module
+
+	return instance, err
+}
+
+// InstantiateMeteredWithImportObjectAndCosts is
+// `InstantiateMeteredWithImportObject`, but accepting a named
+// `OpcodeCosts` table instead of a bare `*[OPCODE_COUNT]uint32`.
+func (module *Module) InstantiateMeteredWithImportObjectAndCosts(
+	importObject *ImportObject,
+	gasLimit uint64,
+	opcodeCosts *OpcodeCosts,
+) (Instance, error) {
+	return module.InstantiateMeteredWithImportObject(importObject, gasLimit, opcodeCosts.ToArray())
+}
+
+// Close closes/frees a `Module`. Instances already created from it are
+// not closed and stay usable (`NewInstanceWithResolver` closes its
+// module before returning the instance); the module itself must not be
+// instantiated or serialized after `Close`.
+func (module *Module) Close() {
+	if module.module != nil {
+		cWasmerModuleDestroy(module.module)
+	}
+}
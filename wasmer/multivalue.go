@@ -0,0 +1,154 @@
+package wasmer
+
+import "unsafe"
+
+// Values holds the results of a WebAssembly exported function call
+// that returns more than one value, as standardized by the wasm
+// multi-value proposal and used by toolchains such as TinyGo and Rust
+// to return tuples directly instead of round-tripping through linear
+// memory.
+type Values []Value
+
+// getExportedFunctionOutputSignature is the multi-value counterpart of
+// `getExportedFunctionOutputArity`: instead of only reporting how many
+// results `wasmFunction` produces, it reports the WebAssembly type of
+// each one, in order, so a caller can convert every result without
+// guessing its type from context.
+func getExportedFunctionOutputSignature(
+	wasmFunction *cWasmerExportFuncT,
+	exportedFunctionName string,
+) ([]cWasmerValueTag, error) {
+	wasmFunctionOutputsArity, err := getExportedFunctionOutputArity(wasmFunction, exportedFunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	if wasmFunctionOutputsArity == 0 {
+		return nil, nil
+	}
+
+	signature := make([]cWasmerValueTag, wasmFunctionOutputsArity)
+
+	if cWasmerExportFuncReturns(wasmFunction, &signature[0], cUint(wasmFunctionOutputsArity)) != cWasmerOk {
+		return nil, NewExportedFunctionError(exportedFunctionName, "Failed to read the output signature of the `%s` exported function.")
+	}
+
+	return signature, nil
+}
+
+// createExportedFunctionMultiWrapper wraps `wasmFunction` into a Go
+// closure returning the full `Values` slice of its result, instead of
+// squashing it down to a single `Value`. It backs `Instance.ExportsMulti`.
+//
+// A build of the underlying Wasmer C API that predates the wasm
+// multi-value proposal only ever allocates room for a single result in
+// its call path; handing it an arity>1 output buffer wouldn't fail,
+// it would silently read past what the C side wrote. So an export
+// declaring more than one result is rejected up front on such a build,
+// via `cWasmerSupportsMultiValue`, rather than read back possibly
+// uninitialized values.
+func createExportedFunctionMultiWrapper(
+	c_instance *cWasmerInstanceT,
+	wasmFunction *cWasmerExportFuncT,
+	exportedFunctionName string,
+) (func(...interface{}) (Values, error), error) {
+	wasmFunctionInputSignatures, wasmFunctionInputsArity, err := getExportedFunctionSignature(wasmFunction, exportedFunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	wasmFunctionOutputSignature, err := getExportedFunctionOutputSignature(wasmFunction, exportedFunctionName)
+	if err != nil {
+		return nil, err
+	}
+
+	wasmFunctionOutputsArity := len(wasmFunctionOutputSignature)
+
+	if wasmFunctionOutputsArity > 1 && !cWasmerSupportsMultiValue() {
+		return nil, NewExportedFunctionError(
+			exportedFunctionName,
+			"`%s` returns more than one value, but this build of the Wasmer C API was not compiled with support for the multi-value proposal.",
+		)
+	}
+
+	wrapper := func(arguments ...interface{}) (Values, error) {
+		err := validateGivenArguments(exportedFunctionName, arguments, wasmFunctionInputsArity)
+		if err != nil {
+			return nil, err
+		}
+
+		wasmInputs, err := createWasmInputsFromArguments(arguments, wasmFunctionInputsArity, wasmFunctionInputSignatures, exportedFunctionName)
+		if err != nil {
+			return nil, err
+		}
+
+		wasmOutputs, callResult := callWasmFunction(
+			c_instance,
+			exportedFunctionName,
+			wasmFunctionInputsArity,
+			wasmFunctionOutputsArity,
+			wasmInputs,
+		)
+
+		if callResult != cWasmerOk {
+			return nil, NewExportedFunctionError(exportedFunctionName, "Failed to call the `%s` exported function.")
+		}
+
+		values := make(Values, wasmFunctionOutputsArity)
+		for nth, tag := range wasmFunctionOutputSignature {
+			values[nth] = convertWasmOutputAt(wasmOutputs, nth, tag)
+		}
+
+		return values, nil
+	}
+
+	return wrapper, nil
+}
+
+// convertWasmOutputAt reads the `nth` result out of `wasmOutputs`,
+// interpreting its raw bytes according to `tag`.
+func convertWasmOutputAt(wasmOutputs []cWasmerValueT, nth int, tag cWasmerValueTag) Value {
+	output := wasmOutputs[nth]
+
+	switch tag {
+	case cWasmerValueTagI32:
+		return NewI32(*(*int32)(unsafe.Pointer(&output.value)))
+	case cWasmerValueTagI64:
+		return NewI64(*(*int64)(unsafe.Pointer(&output.value)))
+	case cWasmerValueTagF32:
+		return NewF32(*(*float32)(unsafe.Pointer(&output.value)))
+	case cWasmerValueTagF64:
+		return NewF64(*(*float64)(unsafe.Pointer(&output.value)))
+	default:
+		return void()
+	}
+}
+
+// singleValueWrapper adapts a multi-value wrapper to the arity-0/1
+// convenience path used by `Instance.Exports`: arity 0 returns
+// `void()`, arity 1 returns its only value, and arity >= 2 is an
+// error, since a single `Value` cannot represent a tuple — callers of
+// a function with more than one result must use `ExportsMulti`.
+func singleValueWrapper(
+	multi func(...interface{}) (Values, error),
+	exportedFunctionName string,
+) func(...interface{}) (Value, error) {
+	return func(arguments ...interface{}) (Value, error) {
+		values, err := multi(arguments...)
+		if err != nil {
+			return void(), err
+		}
+
+		switch len(values) {
+		case 0:
+			return void(), nil
+		case 1:
+			return values[0], nil
+		default:
+			return void(), NewExportedFunctionError(
+				exportedFunctionName,
+				"`%s` returns more than one value; call it through `Instance.ExportsMulti` instead of `Exports`.",
+			)
+		}
+	}
+}
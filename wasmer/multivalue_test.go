@@ -0,0 +1,49 @@
+package wasmer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSingleValueWrapperArities(t *testing.T) {
+	cases := []struct {
+		name    string
+		values  Values
+		wantErr bool
+	}{
+		{name: "arity 0", values: Values{}, wantErr: false},
+		{name: "arity 1", values: Values{void()}, wantErr: false},
+		{name: "arity 2", values: Values{void(), void()}, wantErr: true},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			multi := func(...interface{}) (Values, error) {
+				return testCase.values, nil
+			}
+
+			wrapper := singleValueWrapper(multi, "multi")
+			_, err := wrapper()
+
+			if testCase.wantErr && err == nil {
+				t.Fatalf("expected an error for %d values, got none", len(testCase.values))
+			}
+
+			if !testCase.wantErr && err != nil {
+				t.Fatalf("expected no error for %d values, got %v", len(testCase.values), err)
+			}
+		})
+	}
+}
+
+func TestSingleValueWrapperPropagatesError(t *testing.T) {
+	wantErr := errors.New("boom")
+	multi := func(...interface{}) (Values, error) {
+		return nil, wantErr
+	}
+
+	_, err := singleValueWrapper(multi, "multi")()
+	if err != wantErr {
+		t.Fatalf("expected the underlying error to be propagated, got %v", err)
+	}
+}
@@ -0,0 +1,497 @@
+package wasmer
+
+import "fmt"
+
+// Opcode identifies a single WebAssembly instruction for the purpose
+// of gas metering. Its values are indices into the `OPCODE_COUNT`-sized
+// cost table consumed by `NewMeteredInstanceWithImports` and friends,
+// in the same order the underlying wasmer build expects them.
+type Opcode int
+
+const (
+	OpcodeUnreachable Opcode = iota
+	OpcodeNop
+	OpcodeBlock
+	OpcodeLoop
+	OpcodeIf
+	OpcodeElse
+	OpcodeEnd
+	OpcodeBr
+	OpcodeBrIf
+	OpcodeBrTable
+	OpcodeReturn
+	OpcodeCall
+	OpcodeCallIndirect
+
+	OpcodeDrop
+	OpcodeSelect
+
+	OpcodeLocalGet
+	OpcodeLocalSet
+	OpcodeLocalTee
+	OpcodeGlobalGet
+	OpcodeGlobalSet
+
+	OpcodeI32Load
+	OpcodeI64Load
+	OpcodeF32Load
+	OpcodeF64Load
+	OpcodeI32Load8S
+	OpcodeI32Load8U
+	OpcodeI32Load16S
+	OpcodeI32Load16U
+	OpcodeI64Load8S
+	OpcodeI64Load8U
+	OpcodeI64Load16S
+	OpcodeI64Load16U
+	OpcodeI64Load32S
+	OpcodeI64Load32U
+	OpcodeI32Store
+	OpcodeI64Store
+	OpcodeF32Store
+	OpcodeF64Store
+	OpcodeI32Store8
+	OpcodeI32Store16
+	OpcodeI64Store8
+	OpcodeI64Store16
+	OpcodeI64Store32
+	OpcodeMemorySize
+	OpcodeMemoryGrow
+
+	OpcodeI32Const
+	OpcodeI64Const
+	OpcodeF32Const
+	OpcodeF64Const
+
+	OpcodeI32Eqz
+	OpcodeI32Eq
+	OpcodeI32Ne
+	OpcodeI32LtS
+	OpcodeI32LtU
+	OpcodeI32GtS
+	OpcodeI32GtU
+	OpcodeI32LeS
+	OpcodeI32LeU
+	OpcodeI32GeS
+	OpcodeI32GeU
+
+	OpcodeI64Eqz
+	OpcodeI64Eq
+	OpcodeI64Ne
+	OpcodeI64LtS
+	OpcodeI64LtU
+	OpcodeI64GtS
+	OpcodeI64GtU
+	OpcodeI64LeS
+	OpcodeI64LeU
+	OpcodeI64GeS
+	OpcodeI64GeU
+
+	OpcodeF32Eq
+	OpcodeF32Ne
+	OpcodeF32Lt
+	OpcodeF32Gt
+	OpcodeF32Le
+	OpcodeF32Ge
+
+	OpcodeF64Eq
+	OpcodeF64Ne
+	OpcodeF64Lt
+	OpcodeF64Gt
+	OpcodeF64Le
+	OpcodeF64Ge
+
+	OpcodeI32Clz
+	OpcodeI32Ctz
+	OpcodeI32Popcnt
+	OpcodeI32Add
+	OpcodeI32Sub
+	OpcodeI32Mul
+	OpcodeI32DivS
+	OpcodeI32DivU
+	OpcodeI32RemS
+	OpcodeI32RemU
+	OpcodeI32And
+	OpcodeI32Or
+	OpcodeI32Xor
+	OpcodeI32Shl
+	OpcodeI32ShrS
+	OpcodeI32ShrU
+	OpcodeI32Rotl
+	OpcodeI32Rotr
+
+	OpcodeI64Clz
+	OpcodeI64Ctz
+	OpcodeI64Popcnt
+	OpcodeI64Add
+	OpcodeI64Sub
+	OpcodeI64Mul
+	OpcodeI64DivS
+	OpcodeI64DivU
+	OpcodeI64RemS
+	OpcodeI64RemU
+	OpcodeI64And
+	OpcodeI64Or
+	OpcodeI64Xor
+	OpcodeI64Shl
+	OpcodeI64ShrS
+	OpcodeI64ShrU
+	OpcodeI64Rotl
+	OpcodeI64Rotr
+
+	OpcodeF32Abs
+	OpcodeF32Neg
+	OpcodeF32Ceil
+	OpcodeF32Floor
+	OpcodeF32Trunc
+	OpcodeF32Nearest
+	OpcodeF32Sqrt
+	OpcodeF32Add
+	OpcodeF32Sub
+	OpcodeF32Mul
+	OpcodeF32Div
+	OpcodeF32Min
+	OpcodeF32Max
+	OpcodeF32Copysign
+
+	OpcodeF64Abs
+	OpcodeF64Neg
+	OpcodeF64Ceil
+	OpcodeF64Floor
+	OpcodeF64Trunc
+	OpcodeF64Nearest
+	OpcodeF64Sqrt
+	OpcodeF64Add
+	OpcodeF64Sub
+	OpcodeF64Mul
+	OpcodeF64Div
+	OpcodeF64Min
+	OpcodeF64Max
+	OpcodeF64Copysign
+
+	OpcodeI32WrapI64
+	OpcodeI32TruncF32S
+	OpcodeI32TruncF32U
+	OpcodeI32TruncF64S
+	OpcodeI32TruncF64U
+	OpcodeI64ExtendI32S
+	OpcodeI64ExtendI32U
+	OpcodeI64TruncF32S
+	OpcodeI64TruncF32U
+	OpcodeI64TruncF64S
+	OpcodeI64TruncF64U
+	OpcodeF32ConvertI32S
+	OpcodeF32ConvertI32U
+	OpcodeF32ConvertI64S
+	OpcodeF32ConvertI64U
+	OpcodeF32DemoteF64
+	OpcodeF64ConvertI32S
+	OpcodeF64ConvertI32U
+	OpcodeF64ConvertI64S
+	OpcodeF64ConvertI64U
+	OpcodeF64PromoteF32
+	OpcodeI32ReinterpretF32
+	OpcodeI64ReinterpretF64
+	OpcodeF32ReinterpretI32
+	OpcodeF64ReinterpretI64
+
+	OpcodeRefNull
+	OpcodeRefIsNull
+	OpcodeRefFunc
+
+	OpcodeMemoryInit
+	OpcodeDataDrop
+	OpcodeMemoryCopy
+	OpcodeMemoryFill
+	OpcodeTableInit
+	OpcodeElemDrop
+	OpcodeTableCopy
+	OpcodeTableGrow
+	OpcodeTableSize
+	OpcodeTableFill
+
+	opcodeCount
+)
+
+// opcodeCount must never exceed OPCODE_COUNT: every named Opcode above
+// occupies a slot in the OPCODE_COUNT-sized cost table the underlying
+// wasmer build indexes into, so running out of room here means an
+// opcode was added without the gas metering table growing to match. A
+// negative array length fails the build instead of silently truncating
+// costs at runtime.
+var _ [OPCODE_COUNT - int(opcodeCount)]struct{}
+
+// opcodeNames maps every `Opcode` named above to the identifier used
+// in `String` and `ParseOpcode`. Slots between `opcodeCount` and
+// `OPCODE_COUNT` are intentionally unnamed: they are reserved for
+// instructions the underlying wasmer build counts for metering but
+// that this package does not yet expose a name for.
+var opcodeNames = map[Opcode]string{
+	OpcodeUnreachable:  "Unreachable",
+	OpcodeNop:          "Nop",
+	OpcodeBlock:        "Block",
+	OpcodeLoop:         "Loop",
+	OpcodeIf:           "If",
+	OpcodeElse:         "Else",
+	OpcodeEnd:          "End",
+	OpcodeBr:           "Br",
+	OpcodeBrIf:         "BrIf",
+	OpcodeBrTable:      "BrTable",
+	OpcodeReturn:       "Return",
+	OpcodeCall:         "Call",
+	OpcodeCallIndirect: "CallIndirect",
+
+	OpcodeDrop:   "Drop",
+	OpcodeSelect: "Select",
+
+	OpcodeLocalGet:  "LocalGet",
+	OpcodeLocalSet:  "LocalSet",
+	OpcodeLocalTee:  "LocalTee",
+	OpcodeGlobalGet: "GlobalGet",
+	OpcodeGlobalSet: "GlobalSet",
+
+	OpcodeI32Load:    "I32Load",
+	OpcodeI64Load:    "I64Load",
+	OpcodeF32Load:    "F32Load",
+	OpcodeF64Load:    "F64Load",
+	OpcodeI32Load8S:  "I32Load8S",
+	OpcodeI32Load8U:  "I32Load8U",
+	OpcodeI32Load16S: "I32Load16S",
+	OpcodeI32Load16U: "I32Load16U",
+	OpcodeI64Load8S:  "I64Load8S",
+	OpcodeI64Load8U:  "I64Load8U",
+	OpcodeI64Load16S: "I64Load16S",
+	OpcodeI64Load16U: "I64Load16U",
+	OpcodeI64Load32S: "I64Load32S",
+	OpcodeI64Load32U: "I64Load32U",
+	OpcodeI32Store:   "I32Store",
+	OpcodeI64Store:   "I64Store",
+	OpcodeF32Store:   "F32Store",
+	OpcodeF64Store:   "F64Store",
+	OpcodeI32Store8:  "I32Store8",
+	OpcodeI32Store16: "I32Store16",
+	OpcodeI64Store8:  "I64Store8",
+	OpcodeI64Store16: "I64Store16",
+	OpcodeI64Store32: "I64Store32",
+	OpcodeMemorySize: "MemorySize",
+	OpcodeMemoryGrow: "MemoryGrow",
+
+	OpcodeI32Const: "I32Const",
+	OpcodeI64Const: "I64Const",
+	OpcodeF32Const: "F32Const",
+	OpcodeF64Const: "F64Const",
+
+	OpcodeI32Eqz: "I32Eqz",
+	OpcodeI32Eq:  "I32Eq",
+	OpcodeI32Ne:  "I32Ne",
+	OpcodeI32LtS: "I32LtS",
+	OpcodeI32LtU: "I32LtU",
+	OpcodeI32GtS: "I32GtS",
+	OpcodeI32GtU: "I32GtU",
+	OpcodeI32LeS: "I32LeS",
+	OpcodeI32LeU: "I32LeU",
+	OpcodeI32GeS: "I32GeS",
+	OpcodeI32GeU: "I32GeU",
+
+	OpcodeI64Eqz: "I64Eqz",
+	OpcodeI64Eq:  "I64Eq",
+	OpcodeI64Ne:  "I64Ne",
+	OpcodeI64LtS: "I64LtS",
+	OpcodeI64LtU: "I64LtU",
+	OpcodeI64GtS: "I64GtS",
+	OpcodeI64GtU: "I64GtU",
+	OpcodeI64LeS: "I64LeS",
+	OpcodeI64LeU: "I64LeU",
+	OpcodeI64GeS: "I64GeS",
+	OpcodeI64GeU: "I64GeU",
+
+	OpcodeF32Eq: "F32Eq",
+	OpcodeF32Ne: "F32Ne",
+	OpcodeF32Lt: "F32Lt",
+	OpcodeF32Gt: "F32Gt",
+	OpcodeF32Le: "F32Le",
+	OpcodeF32Ge: "F32Ge",
+
+	OpcodeF64Eq: "F64Eq",
+	OpcodeF64Ne: "F64Ne",
+	OpcodeF64Lt: "F64Lt",
+	OpcodeF64Gt: "F64Gt",
+	OpcodeF64Le: "F64Le",
+	OpcodeF64Ge: "F64Ge",
+
+	OpcodeI32Clz:    "I32Clz",
+	OpcodeI32Ctz:    "I32Ctz",
+	OpcodeI32Popcnt: "I32Popcnt",
+	OpcodeI32Add:    "I32Add",
+	OpcodeI32Sub:    "I32Sub",
+	OpcodeI32Mul:    "I32Mul",
+	OpcodeI32DivS:   "I32DivS",
+	OpcodeI32DivU:   "I32DivU",
+	OpcodeI32RemS:   "I32RemS",
+	OpcodeI32RemU:   "I32RemU",
+	OpcodeI32And:    "I32And",
+	OpcodeI32Or:     "I32Or",
+	OpcodeI32Xor:    "I32Xor",
+	OpcodeI32Shl:    "I32Shl",
+	OpcodeI32ShrS:   "I32ShrS",
+	OpcodeI32ShrU:   "I32ShrU",
+	OpcodeI32Rotl:   "I32Rotl",
+	OpcodeI32Rotr:   "I32Rotr",
+
+	OpcodeI64Clz:    "I64Clz",
+	OpcodeI64Ctz:    "I64Ctz",
+	OpcodeI64Popcnt: "I64Popcnt",
+	OpcodeI64Add:    "I64Add",
+	OpcodeI64Sub:    "I64Sub",
+	OpcodeI64Mul:    "I64Mul",
+	OpcodeI64DivS:   "I64DivS",
+	OpcodeI64DivU:   "I64DivU",
+	OpcodeI64RemS:   "I64RemS",
+	OpcodeI64RemU:   "I64RemU",
+	OpcodeI64And:    "I64And",
+	OpcodeI64Or:     "I64Or",
+	OpcodeI64Xor:    "I64Xor",
+	OpcodeI64Shl:    "I64Shl",
+	OpcodeI64ShrS:   "I64ShrS",
+	OpcodeI64ShrU:   "I64ShrU",
+	OpcodeI64Rotl:   "I64Rotl",
+	OpcodeI64Rotr:   "I64Rotr",
+
+	OpcodeF32Abs:      "F32Abs",
+	OpcodeF32Neg:      "F32Neg",
+	OpcodeF32Ceil:     "F32Ceil",
+	OpcodeF32Floor:    "F32Floor",
+	OpcodeF32Trunc:    "F32Trunc",
+	OpcodeF32Nearest:  "F32Nearest",
+	OpcodeF32Sqrt:     "F32Sqrt",
+	OpcodeF32Add:      "F32Add",
+	OpcodeF32Sub:      "F32Sub",
+	OpcodeF32Mul:      "F32Mul",
+	OpcodeF32Div:      "F32Div",
+	OpcodeF32Min:      "F32Min",
+	OpcodeF32Max:      "F32Max",
+	OpcodeF32Copysign: "F32Copysign",
+
+	OpcodeF64Abs:      "F64Abs",
+	OpcodeF64Neg:      "F64Neg",
+	OpcodeF64Ceil:     "F64Ceil",
+	OpcodeF64Floor:    "F64Floor",
+	OpcodeF64Trunc:    "F64Trunc",
+	OpcodeF64Nearest:  "F64Nearest",
+	OpcodeF64Sqrt:     "F64Sqrt",
+	OpcodeF64Add:      "F64Add",
+	OpcodeF64Sub:      "F64Sub",
+	OpcodeF64Mul:      "F64Mul",
+	OpcodeF64Div:      "F64Div",
+	OpcodeF64Min:      "F64Min",
+	OpcodeF64Max:      "F64Max",
+	OpcodeF64Copysign: "F64Copysign",
+
+	OpcodeI32WrapI64:        "I32WrapI64",
+	OpcodeI32TruncF32S:      "I32TruncF32S",
+	OpcodeI32TruncF32U:      "I32TruncF32U",
+	OpcodeI32TruncF64S:      "I32TruncF64S",
+	OpcodeI32TruncF64U:      "I32TruncF64U",
+	OpcodeI64ExtendI32S:     "I64ExtendI32S",
+	OpcodeI64ExtendI32U:     "I64ExtendI32U",
+	OpcodeI64TruncF32S:      "I64TruncF32S",
+	OpcodeI64TruncF32U:      "I64TruncF32U",
+	OpcodeI64TruncF64S:      "I64TruncF64S",
+	OpcodeI64TruncF64U:      "I64TruncF64U",
+	OpcodeF32ConvertI32S:    "F32ConvertI32S",
+	OpcodeF32ConvertI32U:    "F32ConvertI32U",
+	OpcodeF32ConvertI64S:    "F32ConvertI64S",
+	OpcodeF32ConvertI64U:    "F32ConvertI64U",
+	OpcodeF32DemoteF64:      "F32DemoteF64",
+	OpcodeF64ConvertI32S:    "F64ConvertI32S",
+	OpcodeF64ConvertI32U:    "F64ConvertI32U",
+	OpcodeF64ConvertI64S:    "F64ConvertI64S",
+	OpcodeF64ConvertI64U:    "F64ConvertI64U",
+	OpcodeF64PromoteF32:     "F64PromoteF32",
+	OpcodeI32ReinterpretF32: "I32ReinterpretF32",
+	OpcodeI64ReinterpretF64: "I64ReinterpretF64",
+	OpcodeF32ReinterpretI32: "F32ReinterpretI32",
+	OpcodeF64ReinterpretI64: "F64ReinterpretI64",
+
+	OpcodeRefNull:   "RefNull",
+	OpcodeRefIsNull: "RefIsNull",
+	OpcodeRefFunc:   "RefFunc",
+
+	OpcodeMemoryInit: "MemoryInit",
+	OpcodeDataDrop:   "DataDrop",
+	OpcodeMemoryCopy: "MemoryCopy",
+	OpcodeMemoryFill: "MemoryFill",
+	OpcodeTableInit:  "TableInit",
+	OpcodeElemDrop:   "ElemDrop",
+	OpcodeTableCopy:  "TableCopy",
+	OpcodeTableGrow:  "TableGrow",
+	OpcodeTableSize:  "TableSize",
+	OpcodeTableFill:  "TableFill",
+}
+
+var opcodeByName map[string]Opcode
+
+func init() {
+	opcodeByName = make(map[string]Opcode, len(opcodeNames))
+	for opcode, name := range opcodeNames {
+		opcodeByName[name] = opcode
+	}
+}
+
+// String returns the name of `op` (e.g. `"I32Add"`), or a generic
+// `"Opcode(n)"` placeholder for reserved slots that have no name yet.
+// It satisfies `fmt.Stringer`.
+func (op Opcode) String() string {
+	if name, ok := opcodeNames[op]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Opcode(%d)", int(op))
+}
+
+// ParseOpcode looks up the `Opcode` named `name`, as produced by
+// `Opcode.String`. It is the inverse of `String` and is meant for
+// loading gas schedules from configuration files.
+func ParseOpcode(name string) (Opcode, bool) {
+	op, ok := opcodeByName[name]
+	return op, ok
+}
+
+// OpcodeCosts is a typed wrapper around the `OPCODE_COUNT`-sized cost
+// table consumed by the metered instance constructors. It replaces
+// bare `*[OPCODE_COUNT]uint32` indexing, which requires callers to
+// know the exact ordering behind `OPCODE_COUNT`, with named accessors.
+type OpcodeCosts struct {
+	costs [OPCODE_COUNT]uint32
+}
+
+// NewOpcodeCosts constructs an `OpcodeCosts` with every cost set to 0.
+func NewOpcodeCosts() *OpcodeCosts {
+	return &OpcodeCosts{}
+}
+
+// Set assigns the gas cost of `op` to `cost`.
+func (opcodeCosts *OpcodeCosts) Set(op Opcode, cost uint32) {
+	opcodeCosts.costs[op] = cost
+}
+
+// Get returns the gas cost currently assigned to `op`.
+func (opcodeCosts *OpcodeCosts) Get(op Opcode) uint32 {
+	return opcodeCosts.costs[op]
+}
+
+// Fill assigns `cost` to every opcode, useful as a baseline before
+// overriding the handful of opcodes a gas schedule actually cares
+// about.
+func (opcodeCosts *OpcodeCosts) Fill(cost uint32) {
+	for i := range opcodeCosts.costs {
+		opcodeCosts.costs[i] = cost
+	}
+}
+
+// ToArray returns the raw `[OPCODE_COUNT]uint32` table, in the form
+// expected by `NewMeteredInstanceWithImports` and
+// `NewMeteredInstanceWithImportObject`.
+func (opcodeCosts *OpcodeCosts) ToArray() *[OPCODE_COUNT]uint32 {
+	return &opcodeCosts.costs
+}
@@ -0,0 +1,210 @@
+package wasmer
+
+import "sync"
+
+// InstancePool pools instances created from the same `Module` so that
+// repeated metered executions of the same contract amortize the cost
+// of `cWasmerInstantiate`, which blockchain workloads otherwise pay on
+// every call.
+//
+// A pool only makes sense for modules with no externally observable
+// side effects beyond linear memory: `Release` rewinds memory to the
+// state it was in right after instantiation, but it does not and
+// cannot undo anything the module did through imported host functions
+// (e.g. writes to host-side storage). It also does NOT reset mutable
+// globals, and an instance whose memory grew past its baseline (via
+// `memory.grow`) is destroyed rather than pooled, since neither can be
+// rewound without re-instantiating. Do not pool a module that declares
+// mutable globals it expects to start fresh on every call.
+type InstancePool struct {
+	module       *Module
+	importObject *ImportObject
+	maxIdle      int
+
+	mutex sync.Mutex
+	free  []*pooledInstance
+	live  map[*Instance]*pooledInstance
+	stats PoolStats
+}
+
+// pooledInstance tracks the bookkeeping a pooled `Instance` needs on
+// top of the instance itself: a baseline snapshot of its linear
+// memory, taken the first time the instance was acquired, that
+// `Release` uses to undo whatever the module wrote without paying for
+// a fresh instantiation.
+type pooledInstance struct {
+	instance *Instance
+	baseline []byte
+}
+
+// PoolStats reports counters useful for tuning an `InstancePool`'s
+// `maxIdle` bound.
+type PoolStats struct {
+	Created  int
+	Acquired int
+	Released int
+	Idle     int
+}
+
+// NewInstancePool constructs an `InstancePool` that instantiates
+// `module` with `importObject` on demand, keeping at most `maxIdle`
+// instances around between uses.
+func NewInstancePool(module *Module, importObject *ImportObject, maxIdle int) *InstancePool {
+	return &InstancePool{
+		module:       module,
+		importObject: importObject,
+		maxIdle:      maxIdle,
+		live:         make(map[*Instance]*pooledInstance),
+	}
+}
+
+// Acquire returns an `Instance` ready to run with the given gas limit
+// and opcode costs. If the free list is non-empty, the returned
+// instance is a reused one whose gas limit and costs are simply reset
+// — no `cWasmerInstantiate` call is made. Otherwise a fresh instance is
+// instantiated from the pool's already-compiled `Module` and
+// `ImportObject`.
+func (pool *InstancePool) Acquire(gasLimit uint64, opcodeCosts *[OPCODE_COUNT]uint32) (*Instance, error) {
+	pool.mutex.Lock()
+	if n := len(pool.free); n > 0 {
+		pooled := pool.free[n-1]
+		pool.free = pool.free[:n-1]
+		pool.stats.Idle--
+		pool.stats.Acquired++
+		pool.live[pooled.instance] = pooled
+		pool.mutex.Unlock()
+
+		pooled.instance.SetPointsUsed(0)
+		pooled.instance.SetGasLimitAndCosts(gasLimit, opcodeCosts)
+
+		return pooled.instance, nil
+	}
+	pool.mutex.Unlock()
+
+	instance, err := pool.module.InstantiateMeteredWithImportObject(pool.importObject, gasLimit, opcodeCosts)
+	if err != nil {
+		return nil, err
+	}
+
+	pooled := &pooledInstance{instance: &instance}
+	if instance.HasMemory() {
+		pooled.baseline = append([]byte(nil), instance.Memory.Data()...)
+	}
+
+	pool.mutex.Lock()
+	pool.stats.Created++
+	pool.stats.Acquired++
+	pool.live[pooled.instance] = pooled
+	pool.mutex.Unlock()
+
+	return pooled.instance, nil
+}
+
+// Release returns `instance` to the pool instead of destroying it. Any
+// linear memory byte the module dirtied since it was last acquired is
+// rewound to its baseline, `PointsUsed` is reset to zero, and the
+// instance context data is cleared. If the pool already holds
+// `maxIdle` idle instances, or the instance's memory grew past its
+// baseline, `instance` is destroyed instead of kept. Releasing an
+// instance that did not come from this pool is a no-op.
+//
+// `instance` was created via `Module.InstantiateMeteredWithImportObject`
+// with the pool's `ImportObject`, so its `imports` field is shared with
+// every other instance in the pool; destroying it must never free that
+// shared `Imports`. Use `Instance.Clean`, not `Instance.Close`, which
+// would free the host-function C pointers still in use by every other
+// live or idle pooled instance.
+func (pool *InstancePool) Release(instance *Instance) {
+	pool.mutex.Lock()
+	pooled, ok := pool.live[instance]
+	if !ok {
+		pool.mutex.Unlock()
+		return
+	}
+	delete(pool.live, instance)
+	pool.stats.Released++
+	pool.mutex.Unlock()
+
+	if memoryGrew(instance, pooled.baseline) {
+		instance.Clean()
+		return
+	}
+
+	instance.SetPointsUsed(0)
+	instance.SetContextData(nil)
+	resetDirtiedMemory(instance, pooled.baseline)
+
+	// The `maxIdle` bound must be re-checked under the same lock that
+	// appends to `pool.free`: checking it earlier (e.g. right after
+	// removing `instance` from `pool.live`, before rewinding its
+	// memory) lets two concurrent `Release` calls both observe room
+	// and push the free list past `maxIdle`.
+	pool.mutex.Lock()
+	if len(pool.free) >= pool.maxIdle {
+		pool.mutex.Unlock()
+		instance.Clean()
+		return
+	}
+	pool.free = append(pool.free, pooled)
+	pool.stats.Idle++
+	pool.mutex.Unlock()
+}
+
+// memoryGrew reports whether the instance's linear memory is a
+// different size than its baseline snapshot, i.e. the module called
+// `memory.grow`. Wasm memory cannot shrink, so such an instance can
+// never be brought back to its baseline shape and must not be pooled.
+func memoryGrew(instance *Instance, baseline []byte) bool {
+	if instance.Memory == nil {
+		return baseline != nil
+	}
+
+	return len(instance.Memory.Data()) != len(baseline)
+}
+
+// resetDirtiedMemory rewrites every byte of the instance's linear
+// memory that differs from the baseline snapshot taken on first
+// acquisition, leaving untouched pages alone. It assumes the memory is
+// still the size it was at that snapshot; callers must check
+// `memoryGrew` first.
+func resetDirtiedMemory(instance *Instance, baseline []byte) {
+	if instance.Memory == nil || baseline == nil {
+		return
+	}
+
+	current := instance.Memory.Data()
+	limit := len(baseline)
+	if len(current) < limit {
+		limit = len(current)
+	}
+
+	for i := 0; i < limit; i++ {
+		if current[i] != baseline[i] {
+			current[i] = baseline[i]
+		}
+	}
+}
+
+// Stats returns a snapshot of the pool's counters.
+func (pool *InstancePool) Stats() PoolStats {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	return pool.stats
+}
+
+// Close drains the free list and destroys every idle instance, via
+// `Instance.Clean` so the `ImportObject` shared across the pool is left
+// intact. An instance still held by a caller (not yet released) is
+// unaffected; it remains the caller's responsibility to `Release` or
+// close directly.
+func (pool *InstancePool) Close() {
+	pool.mutex.Lock()
+	free := pool.free
+	pool.free = nil
+	pool.mutex.Unlock()
+
+	for _, pooled := range free {
+		pooled.instance.Clean()
+	}
+}
@@ -0,0 +1,15 @@
+package wasmer
+
+import "testing"
+
+func TestMemoryGrewWithoutMemory(t *testing.T) {
+	instance := &Instance{}
+
+	if memoryGrew(instance, nil) {
+		t.Fatalf("expected no growth to be reported when the instance never had memory")
+	}
+
+	if !memoryGrew(instance, []byte{0, 0}) {
+		t.Fatalf("expected growth to be reported when a baseline existed but the instance now has no memory")
+	}
+}
@@ -0,0 +1,65 @@
+package wasmertest
+
+import (
+	wasm "github.com/ElrondNetwork/go-ext-wasm/wasmer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpcodeStringAndParseRoundTrip(t *testing.T) {
+	name := wasm.OpcodeI64Add.String()
+	assert.Equal(t, "I64Add", name)
+
+	op, ok := wasm.ParseOpcode(name)
+	assert.True(t, ok)
+	assert.Equal(t, wasm.OpcodeI64Add, op)
+}
+
+func TestParseOpcodeUnknownName(t *testing.T) {
+	_, ok := wasm.ParseOpcode("NotAnOpcode")
+	assert.False(t, ok)
+}
+
+// TestOpcodeIndicesMatchWasmerMeteringOrder pins a handful of named
+// opcodes to the exact index the underlying wasmer build's metering
+// table expects them at. The `const` block in opcode.go must list
+// every variant in that same order with none missing or duplicated;
+// if it ever drifts, every gas cost set through `OpcodeCosts` would be
+// silently misassigned without this catching it first.
+func TestOpcodeIndicesMatchWasmerMeteringOrder(t *testing.T) {
+	assert.Equal(t, wasm.Opcode(0), wasm.OpcodeUnreachable)
+	assert.Equal(t, wasm.Opcode(12), wasm.OpcodeCallIndirect)
+	assert.Equal(t, wasm.Opcode(86), wasm.OpcodeI32Add)
+	assert.Equal(t, wasm.Opcode(104), wasm.OpcodeI64Add)
+	assert.Equal(t, wasm.Opcode(146), wasm.OpcodeF64Copysign)
+	assert.Equal(t, wasm.Opcode(175), wasm.OpcodeMemoryInit)
+	assert.Equal(t, wasm.Opcode(184), wasm.OpcodeTableFill)
+}
+
+func TestOpcodeCostsSetGetFill(t *testing.T) {
+	costs := wasm.NewOpcodeCosts()
+	costs.Fill(1)
+	costs.Set(wasm.OpcodeI64Add, 42)
+
+	assert.Equal(t, uint32(42), costs.Get(wasm.OpcodeI64Add))
+	assert.Equal(t, uint32(1), costs.Get(wasm.OpcodeNop))
+}
+
+// TestOpcodeCostsToArrayPlumbing exercises the conversion the metered
+// constructors rely on (`NewMeteredInstanceWithImportsAndCosts`,
+// `NewMeteredInstanceWithImportObjectAndCosts` and
+// `Module.InstantiateMeteredWithImportObjectAndCosts`, all of which
+// just forward `ToArray()`'s result): the array `ToArray` returns must
+// reflect every `Set` call, in the `OPCODE_COUNT`-sized raw layout the
+// underlying wasmer build expects, and further `Set` calls must keep
+// mutating the same backing array rather than a copy.
+func TestOpcodeCostsToArrayPlumbing(t *testing.T) {
+	costs := wasm.NewOpcodeCosts()
+	costs.Set(wasm.OpcodeI32Add, 7)
+
+	array := costs.ToArray()
+	assert.Equal(t, uint32(7), array[wasm.OpcodeI32Add])
+
+	costs.Set(wasm.OpcodeI32Add, 9)
+	assert.Equal(t, uint32(9), array[wasm.OpcodeI32Add], "ToArray must alias OpcodeCosts' storage, not copy it")
+}
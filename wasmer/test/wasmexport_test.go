@@ -0,0 +1,42 @@
+package wasmertest
+
+import (
+	wasm "github.com/ElrondNetwork/go-ext-wasm/wasmer"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAppendExportRejectsNonFunction(t *testing.T) {
+	err := wasm.NewImports().AppendExport("env", "transfer", 42)
+
+	assert.EqualError(t, err, "`env`.`transfer` must be a function; given `int`.")
+}
+
+func TestAppendExportRejectsMultipleReturnValues(t *testing.T) {
+	err := wasm.NewImports().AppendExport("env", "transfer", func() (int32, int32) { return 0, 0 })
+
+	assert.EqualError(t, err, "`env`.`transfer` must return at most one value; it returns 2.")
+}
+
+func TestAppendExportRejectsBadParameterType(t *testing.T) {
+	err := wasm.NewImports().AppendExport("env", "transfer", func(int32, uintptr) int32 { return 0 })
+
+	assert.EqualError(t, err, "parameter 2 of `transfer`: uintptr is not a permitted wasmexport type.")
+}
+
+func TestAppendExportRejectsBadReturnType(t *testing.T) {
+	err := wasm.NewImports().AppendExport("env", "transfer", func(int32) uintptr { return 0 })
+
+	assert.EqualError(t, err, "return value of `transfer`: uintptr is not a permitted wasmexport type.")
+}
+
+func TestAppendExportRejectsEvenAPermittedSignature(t *testing.T) {
+	// AppendExport validates a signature but cannot register it yet:
+	// dispatching a call from Wasm into reflected Go code needs a cgo
+	// trampoline that doesn't exist in this build. A permitted
+	// signature must still come back as an error, not a silent no-op
+	// that looks like a working registration.
+	err := wasm.NewImports().AppendExport("env", "add", func(a int32, b int64, c float32, d float64) int32 { return a })
+
+	assert.EqualError(t, err, "`env`.`add`: AppendExport cannot dispatch calls from Wasm yet; the cgo trampoline it needs has not been implemented.")
+}
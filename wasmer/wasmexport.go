@@ -0,0 +1,65 @@
+package wasmer
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// wasmexportKinds maps the Go kinds permitted by `AppendExport` to the
+// WebAssembly type they represent. This mirrors the restriction the
+// `go:wasmexport` directive places on parameter and result types in
+// the upstream Go toolchain: only the four WebAssembly numeric types.
+var wasmexportKinds = map[reflect.Kind]cWasmerValueTag{
+	reflect.Int32:   cWasmerValueTagI32,
+	reflect.Uint32:  cWasmerValueTagI32,
+	reflect.Int64:   cWasmerValueTagI64,
+	reflect.Uint64:  cWasmerValueTagI64,
+	reflect.Float32: cWasmerValueTagF32,
+	reflect.Float64: cWasmerValueTagF64,
+}
+
+// AppendExport would register `fn` as a function the WebAssembly
+// module can call by `namespace`/`name`, mirroring the `go:wasmexport`
+// directive in the upstream Go toolchain: `fn`'s parameters and, if
+// present, its single return value are restricted to
+// `i32`/`i64`/`f32`/`f64` - as Go
+// `int32`/`uint32`/`int64`/`uint64`/`float32`/`float64` - matching the
+// types the wasmexport proposal permits.
+//
+// It does not register anything yet. Unlike `Append`, which takes a
+// hand-written cgo trampoline matching `func(context unsafe.Pointer,
+// args ...) ...`, dispatching a reflected Go value requires a generic
+// trampoline declared with `//export` in the cgo wrapper layer this
+// package builds against - and wasmer's import calling convention
+// hands an import a single, fixed C function pointer, not one minted
+// per registered Go value, so that trampoline can't be synthesized
+// from pure Go. That wrapper-layer change hasn't landed, so for now
+// `AppendExport` only validates that `fn`'s signature could be
+// exported, and always returns an error afterwards so a caller can't
+// mistake a validated signature for a working registration.
+func (imports *Imports) AppendExport(namespace string, name string, fn interface{}) error {
+	fnValue := reflect.ValueOf(fn)
+	fnType := fnValue.Type()
+
+	if fnType.Kind() != reflect.Func {
+		return fmt.Errorf("`%s`.`%s` must be a function; given `%s`.", namespace, name, fnType.Kind())
+	}
+
+	if fnType.NumOut() > 1 {
+		return fmt.Errorf("`%s`.`%s` must return at most one value; it returns %d.", namespace, name, fnType.NumOut())
+	}
+
+	for nth := 0; nth < fnType.NumIn(); nth++ {
+		if _, ok := wasmexportKinds[fnType.In(nth).Kind()]; !ok {
+			return fmt.Errorf("parameter %d of `%s`: %s is not a permitted wasmexport type.", nth+1, name, fnType.In(nth))
+		}
+	}
+
+	if fnType.NumOut() == 1 {
+		if _, ok := wasmexportKinds[fnType.Out(0).Kind()]; !ok {
+			return fmt.Errorf("return value of `%s`: %s is not a permitted wasmexport type.", name, fnType.Out(0))
+		}
+	}
+
+	return fmt.Errorf("`%s`.`%s`: AppendExport cannot dispatch calls from Wasm yet; the cgo trampoline it needs has not been implemented.", namespace, name)
+}